@@ -0,0 +1,840 @@
+package seth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Kind identifies the fundamental category of a parsed ABI type.
+type Kind int
+
+const (
+	KindAddress Kind = iota
+	KindBool
+	KindUint
+	KindInt
+	KindBytesN // fixed-size bytes1..bytes32
+	KindBytes  // dynamically-sized bytes
+	KindString
+	KindArray // T[N]
+	KindSlice // T[]
+	KindTuple // (T0,T1,...)
+)
+
+// Type is a single parsed component of a function or tuple
+// signature, such as the "uint256" or "(address,bool)[3]" in
+// "transfer(uint256,(address,bool)[3])".
+type Type struct {
+	Kind  Kind
+	Width int    // bit width for Uint/Int, byte width for BytesN
+	Len   int    // array length, for Array
+	Elem  *Type  // element type, for Array/Slice
+	Tuple []Type // component types, for Tuple
+}
+
+// Dynamic reports whether a value of this type is ABI-encoded as a
+// 32-byte offset in the head, with its payload written to the tail.
+func (t Type) Dynamic() bool {
+	switch t.Kind {
+	case KindString, KindBytes, KindSlice:
+		return true
+	case KindArray:
+		return t.Elem.Dynamic()
+	case KindTuple:
+		for _, c := range t.Tuple {
+			if c.Dynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IndexedAsHash reports whether an indexed event argument of this
+// type is recorded in its log topic as a keccak256 hash rather than
+// as its ABI-encoded word: a hash of the raw contents for a string
+// or dynamic bytes, or of the encoding for an array or tuple (see
+// TopicOf). This is
+// Solidity's reference-type rule: strings, dynamic bytes, arrays
+// (fixed- or dynamic-size), and tuples are always hashed when
+// indexed, regardless of whether the type itself is Dynamic(); only
+// the value types (address, bool, the integer types, and fixed-size
+// bytesN) are stored as-is.
+func (t Type) IndexedAsHash() bool {
+	switch t.Kind {
+	case KindAddress, KindBool, KindUint, KindInt, KindBytesN:
+		return false
+	default:
+		return true
+	}
+}
+
+// Sig returns the canonical signature component for this type, e.g.
+// a tuple type prints as "(uint256,address)" and an alias like
+// "uint" expands to "uint256", exactly as solc does when computing
+// a function selector.
+func (t Type) Sig() string {
+	switch t.Kind {
+	case KindAddress:
+		return "address"
+	case KindBool:
+		return "bool"
+	case KindString:
+		return "string"
+	case KindBytes:
+		return "bytes"
+	case KindBytesN:
+		return fmt.Sprintf("bytes%d", t.Width)
+	case KindUint:
+		return fmt.Sprintf("uint%d", t.Width)
+	case KindInt:
+		return fmt.Sprintf("int%d", t.Width)
+	case KindArray:
+		return fmt.Sprintf("%s[%d]", t.Elem.Sig(), t.Len)
+	case KindSlice:
+		return t.Elem.Sig() + "[]"
+	case KindTuple:
+		parts := make([]string, len(t.Tuple))
+		for i, c := range t.Tuple {
+			parts[i] = c.Sig()
+		}
+		return "(" + strings.Join(parts, ",") + ")"
+	}
+	panic("seth: invalid Type")
+}
+
+// ParseSig parses a Solidity-style function signature, e.g.
+// "transfer(address,uint256)", into its name and argument types.
+func ParseSig(sig string) (name string, args []Type, err error) {
+	lparen := strings.IndexByte(sig, '(')
+	if lparen == -1 {
+		return "", nil, fmt.Errorf("seth: %q has no left paren", sig)
+	}
+	if sig[len(sig)-1] != ')' {
+		return "", nil, fmt.Errorf("seth: %q has a bad right paren", sig)
+	}
+	args, err = parseTypeList(sig[lparen+1 : len(sig)-1])
+	if err != nil {
+		return "", nil, err
+	}
+	return sig[:lparen], args, nil
+}
+
+// parseTypeList parses a comma-separated list of types, respecting
+// nested parens and brackets.
+func parseTypeList(s string) ([]Type, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := splitTopLevel(s)
+	out := make([]Type, len(parts))
+	for i, p := range parts {
+		t, err := parseType(p)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = t
+	}
+	return out, nil
+}
+
+// splitTopLevel splits s on commas that are not nested inside
+// parens or brackets.
+func splitTopLevel(s string) []string {
+	var out []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(out, s[start:])
+}
+
+// parseType parses a single ABI type, including any trailing array
+// dimensions (e.g. "uint256[3][]" or "(bool,bytes)[]").
+func parseType(s string) (Type, error) {
+	var dims []int // -1 marks a dynamic dimension; outermost first
+	for len(s) > 0 && s[len(s)-1] == ']' {
+		lb := strings.LastIndexByte(s, '[')
+		if lb == -1 {
+			return Type{}, fmt.Errorf("seth: unbalanced brackets in %q", s)
+		}
+		inner := s[lb+1 : len(s)-1]
+		s = s[:lb]
+		if inner == "" {
+			dims = append(dims, -1)
+			continue
+		}
+		n, err := strconv.Atoi(inner)
+		if err != nil || n <= 0 {
+			return Type{}, fmt.Errorf("seth: bad array length %q", inner)
+		}
+		dims = append(dims, n)
+	}
+
+	var base Type
+	if strings.HasPrefix(s, "(") {
+		if !strings.HasSuffix(s, ")") {
+			return Type{}, fmt.Errorf("seth: unbalanced parens in %q", s)
+		}
+		comps, err := parseTypeList(s[1 : len(s)-1])
+		if err != nil {
+			return Type{}, err
+		}
+		base = Type{Kind: KindTuple, Tuple: comps}
+	} else {
+		var err error
+		base, err = parseBaseType(s)
+		if err != nil {
+			return Type{}, err
+		}
+	}
+
+	// dims were collected outermost-first, so wrap inside-out
+	for i := len(dims) - 1; i >= 0; i-- {
+		elem := base
+		if dims[i] == -1 {
+			base = Type{Kind: KindSlice, Elem: &elem}
+		} else {
+			base = Type{Kind: KindArray, Elem: &elem, Len: dims[i]}
+		}
+	}
+	return base, nil
+}
+
+func parseBaseType(s string) (Type, error) {
+	switch {
+	case s == "address":
+		return Type{Kind: KindAddress}, nil
+	case s == "bool":
+		return Type{Kind: KindBool}, nil
+	case s == "string":
+		return Type{Kind: KindString}, nil
+	case s == "bytes":
+		return Type{Kind: KindBytes}, nil
+	case s == "uint" || s == "int":
+		return Type{Kind: intKindOf(s), Width: 256}, nil
+	case strings.HasPrefix(s, "uint"), strings.HasPrefix(s, "int"):
+		kind := intKindOf(s)
+		digits := strings.TrimPrefix(strings.TrimPrefix(s, "uint"), "int")
+		bits, err := strconv.Atoi(digits)
+		if err != nil || bits <= 0 || bits > 256 || bits%8 != 0 {
+			return Type{}, fmt.Errorf("seth: bad integer type %q", s)
+		}
+		return Type{Kind: kind, Width: bits}, nil
+	case strings.HasPrefix(s, "bytes"):
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "bytes"))
+		if err != nil || n <= 0 || n > 32 {
+			return Type{}, fmt.Errorf("seth: bad fixed bytes type %q", s)
+		}
+		return Type{Kind: KindBytesN, Width: n}, nil
+	}
+	return Type{}, fmt.Errorf("seth: unknown type %q", s)
+}
+
+func intKindOf(s string) Kind {
+	if strings.HasPrefix(s, "uint") {
+		return KindUint
+	}
+	return KindInt
+}
+
+func repeatType(t Type, n int) []Type {
+	out := make([]Type, n)
+	for i := range out {
+		out[i] = t
+	}
+	return out
+}
+
+// Bool is an EtherType implementation of the Solidity bool type.
+type Bool bool
+
+// EncodeABI implements EtherType.
+func (b *Bool) EncodeABI(v []byte) []byte {
+	var w [32]byte
+	if *b {
+		w[31] = 1
+	}
+	return append(v, w[:]...)
+}
+
+func (b *Bool) internal() {}
+
+// Str is an EtherType implementation of the Solidity string type.
+// Like Bytes, it is dynamically sized and right-padded to a multiple
+// of 32 bytes when encoded.
+type Str string
+
+// EncodeABI implements EtherType. As with EtherSlice, it appends only
+// the raw (padded) content, not the length prefix.
+func (s *Str) EncodeABI(v []byte) []byte { return appendPadded(v, []byte(*s)) }
+
+// Len implements EtherSlice.Len, returning the string's byte length.
+func (s *Str) Len() int { return len(*s) }
+
+func (s *Str) internal() {}
+
+// Bytes is an EtherType implementation of the Solidity dynamic
+// bytes type, as distinct from Data's fixed-size bytes32 slot.
+type Bytes []byte
+
+// EncodeABI implements EtherType.
+func (b *Bytes) EncodeABI(v []byte) []byte { return appendPadded(v, *b) }
+
+// Len implements EtherSlice.Len.
+func (b *Bytes) Len() int { return len(*b) }
+
+func (b *Bytes) internal() {}
+
+func appendPadded(v, content []byte) []byte {
+	v = append(v, content...)
+	if pad := (32 - len(content)%32) % 32; pad > 0 {
+		v = append(v, make([]byte, pad)...)
+	}
+	return v
+}
+
+// Array is an EtherType implementation of a fixed-size Solidity
+// array, T[N].
+type Array struct {
+	Elem Type
+	Vals []EtherType
+}
+
+// EncodeABI implements EtherType.
+func (a *Array) EncodeABI(v []byte) []byte {
+	for _, e := range a.Vals {
+		v = e.EncodeABI(v)
+	}
+	return v
+}
+
+func (a *Array) internal() {}
+
+// Slice is an EtherType implementation of a dynamically-sized
+// Solidity array, T[], whose element type may itself be dynamic
+// (unlike AddrSlice/IntSlice, which only hold static elements).
+type Slice struct {
+	Elem Type
+	Vals []EtherType
+}
+
+// EncodeABI implements EtherType.
+func (s *Slice) EncodeABI(v []byte) []byte {
+	for _, e := range s.Vals {
+		v = e.EncodeABI(v)
+	}
+	return v
+}
+
+// Len implements EtherSlice.Len.
+func (s *Slice) Len() int { return len(s.Vals) }
+
+func (s *Slice) internal() {}
+
+// Tuple is an EtherType implementation of a Solidity tuple/struct
+// type, e.g. "(address,uint256)".
+type Tuple struct {
+	Types []Type
+	Vals  []EtherType
+}
+
+// EncodeABI implements EtherType.
+func (t *Tuple) EncodeABI(v []byte) []byte {
+	buf, err := encodeTuple(t.Types, t.Vals)
+	if err != nil {
+		panic(err)
+	}
+	return append(v, buf...)
+}
+
+func (t *Tuple) internal() {}
+
+// Selector returns the 4-byte function selector for a function with
+// the given name and argument types, canonicalizing aliases and
+// tuples exactly as solc does when computing a selector.
+func Selector(name string, args []Type) [4]byte {
+	parts := make([]string, len(args))
+	for i, t := range args {
+		parts[i] = t.Sig()
+	}
+	h := HashString(name + "(" + strings.Join(parts, ",") + ")")
+	var out [4]byte
+	copy(out[:], h[:4])
+	return out
+}
+
+// ABIEncode encodes a function call: the 4-byte selector for 'fn'
+// followed by the ABI encoding of args. It panics if 'fn' is
+// malformed or if args don't match its declared types, mirroring
+// the existing panic-on-misuse contract of EncodeCall.
+func ABIEncode(fn string, args ...EtherType) []byte {
+	name, types, err := ParseSig(fn)
+	if err != nil {
+		panic(err)
+	}
+	body, err := encodeTuple(types, args)
+	if err != nil {
+		panic(err)
+	}
+	sel := Selector(name, types)
+	return append(sel[:], body...)
+}
+
+// ABIDecode decodes ABI-encoded data (e.g. a call's return value or
+// a log's Data field) according to the argument types named in
+// 'sig', which may be a full function signature or a bare type list
+// such as "(uint256,address)".
+func ABIDecode(sig string, data []byte) ([]EtherType, error) {
+	_, types, err := ParseSig(sig)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTuple(types, data)
+}
+
+// Unpack decodes ABI-encoded data according to 'sig' and reflects
+// each resulting value into the corresponding out parameter, which
+// must be a pointer either to the same EtherType ABIDecode produces
+// for it (*Int, *Bool, *Str, *Array, *Tuple and so on) or to a
+// compatible Go type: Int for integers, bool, string, []byte for
+// bytes and bytesN, Address, slices/arrays thereof, or a struct
+// (field-by-field, in order) for a tuple.
+func Unpack(sig string, data []byte, out ...interface{}) error {
+	vals, err := ABIDecode(sig, data)
+	if err != nil {
+		return err
+	}
+	if len(vals) != len(out) {
+		return fmt.Errorf("seth: %d return values, %d outputs given", len(vals), len(out))
+	}
+	for i := range vals {
+		if err := reflectInto(vals[i], out[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errShortABI = errors.New("seth: ABI-encoded data is too short")
+
+func typeErr(t Type, v EtherType) error {
+	return fmt.Errorf("seth: value %T does not match ABI type %s", v, t.Sig())
+}
+
+// encodeTuple ABI-encodes vals as the components of a tuple (or, at
+// the top level, a function's argument list), writing a 32-byte
+// offset into the head for each dynamic component and deferring its
+// payload to the tail.
+func encodeTuple(types []Type, vals []EtherType) ([]byte, error) {
+	if len(types) != len(vals) {
+		return nil, fmt.Errorf("seth: expected %d values, got %d", len(types), len(vals))
+	}
+	head := make([][]byte, len(vals))
+	tail := make([][]byte, len(vals))
+	for i := range vals {
+		var err error
+		if types[i].Dynamic() {
+			tail[i], err = encodeDynamic(types[i], vals[i])
+		} else {
+			head[i], err = encodeStatic(types[i], vals[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The tail starts after the head, whose size is the sum of each
+	// slot's width: 32 bytes for a dynamic component's offset word,
+	// or len(head[i]) for a static one, which can itself span
+	// multiple words (a static Tuple or a fixed Array of more than
+	// one element).
+	var offset uint
+	for i := range vals {
+		if tail[i] != nil {
+			offset += 32
+		} else {
+			offset += uint(len(head[i]))
+		}
+	}
+	var out []byte
+	for i := range vals {
+		if tail[i] != nil {
+			out = padint(offset, out)
+			offset += uint(len(tail[i]))
+		} else {
+			out = append(out, head[i]...)
+		}
+	}
+	for i := range vals {
+		out = append(out, tail[i]...)
+	}
+	return out, nil
+}
+
+// encodeStatic encodes a value of a statically-sized type, returning
+// exactly the bytes written into the head.
+func encodeStatic(t Type, v EtherType) ([]byte, error) {
+	switch t.Kind {
+	case KindAddress:
+		a, ok := v.(*Address)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return a.EncodeABI(nil), nil
+	case KindBool:
+		b, ok := v.(*Bool)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return b.EncodeABI(nil), nil
+	case KindUint, KindInt:
+		i, ok := v.(*Int)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return encodeInt(t, i), nil
+	case KindBytesN:
+		d, ok := v.(*Data)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		if len(*d) > t.Width {
+			return nil, fmt.Errorf("seth: value overflows %s", t.Sig())
+		}
+		return d.EncodeABI(nil), nil
+	case KindArray:
+		a, ok := v.(*Array)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		if len(a.Vals) != t.Len {
+			return nil, fmt.Errorf("seth: expected array of length %d, got %d", t.Len, len(a.Vals))
+		}
+		return encodeTuple(repeatType(*t.Elem, t.Len), a.Vals)
+	case KindTuple:
+		tup, ok := v.(*Tuple)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return encodeTuple(t.Tuple, tup.Vals)
+	}
+	return nil, fmt.Errorf("seth: type %s is not statically sized", t.Sig())
+}
+
+// encodeDynamic encodes the tail content of a dynamic value.
+func encodeDynamic(t Type, v EtherType) ([]byte, error) {
+	switch t.Kind {
+	case KindString:
+		s, ok := v.(*Str)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return append(padint(uint(s.Len()), nil), s.EncodeABI(nil)...), nil
+	case KindBytes:
+		b, ok := v.(*Bytes)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return append(padint(uint(b.Len()), nil), b.EncodeABI(nil)...), nil
+	case KindSlice:
+		switch s := v.(type) {
+		case *Slice:
+			body, err := encodeTuple(repeatType(*t.Elem, len(s.Vals)), s.Vals)
+			if err != nil {
+				return nil, err
+			}
+			return append(padint(uint(len(s.Vals)), nil), body...), nil
+		case EtherSlice:
+			// legacy flat slice (AddrSlice, IntSlice): static
+			// elements only, so no nested offsets are needed.
+			return append(padint(uint(s.Len()), nil), s.EncodeABI(nil)...), nil
+		}
+		return nil, typeErr(t, v)
+	case KindArray:
+		a, ok := v.(*Array)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		if len(a.Vals) != t.Len {
+			return nil, fmt.Errorf("seth: expected array of length %d, got %d", t.Len, len(a.Vals))
+		}
+		return encodeTuple(repeatType(*t.Elem, t.Len), a.Vals)
+	case KindTuple:
+		tup, ok := v.(*Tuple)
+		if !ok {
+			return nil, typeErr(t, v)
+		}
+		return encodeTuple(t.Tuple, tup.Vals)
+	}
+	return nil, fmt.Errorf("seth: type %s is not dynamically sized", t.Sig())
+}
+
+// encodeInt encodes i as a 32-byte word, two's-complementing
+// negative values exactly as Solidity does for signed integer types.
+func encodeInt(t Type, i *Int) []byte {
+	bi := (*big.Int)(i)
+	var w [32]byte
+	if t.Kind == KindInt && bi.Sign() < 0 {
+		mod := new(big.Int).Lsh(big.NewInt(1), 256)
+		mod.Add(mod, bi)
+		mod.FillBytes(w[:])
+	} else {
+		bi.FillBytes(w[:])
+	}
+	return w[:]
+}
+
+// decodeInt is the inverse of encodeInt.
+func decodeInt(t Type, data []byte) *Int {
+	bi := new(big.Int).SetBytes(data)
+	if t.Kind == KindInt && data[0]&0x80 != 0 {
+		mod := new(big.Int).Lsh(big.NewInt(1), 256)
+		bi.Sub(bi, mod)
+	}
+	i := Int(*bi)
+	return &i
+}
+
+// decodeTuple decodes data as the components of a tuple (or, at the
+// top level, a function's argument or return list).
+func decodeTuple(types []Type, data []byte) ([]EtherType, error) {
+	out := make([]EtherType, len(types))
+	pos := 0
+	for i, t := range types {
+		if t.Dynamic() {
+			if pos+32 > len(data) {
+				return nil, errShortABI
+			}
+			off := new(big.Int).SetBytes(data[pos : pos+32]).Int64()
+			pos += 32
+			if off < 0 || int(off) > len(data) {
+				return nil, fmt.Errorf("seth: offset %d out of range", off)
+			}
+			v, err := decodeDynamic(t, data[off:])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		} else {
+			v, n, err := decodeStatic(t, data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+			pos += n
+		}
+	}
+	return out, nil
+}
+
+// decodeStatic decodes a value of a statically-sized type from the
+// front of data, returning the value and the number of bytes consumed.
+func decodeStatic(t Type, data []byte) (EtherType, int, error) {
+	switch t.Kind {
+	case KindAddress:
+		if len(data) < 32 {
+			return nil, 0, errShortABI
+		}
+		var a Address
+		copy(a[:], data[12:32])
+		return &a, 32, nil
+	case KindBool:
+		if len(data) < 32 {
+			return nil, 0, errShortABI
+		}
+		b := Bool(data[31] != 0)
+		return &b, 32, nil
+	case KindUint, KindInt:
+		if len(data) < 32 {
+			return nil, 0, errShortABI
+		}
+		return decodeInt(t, data[:32]), 32, nil
+	case KindBytesN:
+		if len(data) < 32 {
+			return nil, 0, errShortABI
+		}
+		d := Data(append([]byte(nil), data[:t.Width]...))
+		return &d, 32, nil
+	case KindArray:
+		vals := make([]EtherType, t.Len)
+		n := 0
+		for i := range vals {
+			v, m, err := decodeStatic(*t.Elem, data[n:])
+			if err != nil {
+				return nil, 0, err
+			}
+			vals[i] = v
+			n += m
+		}
+		return &Array{Elem: *t.Elem, Vals: vals}, n, nil
+	case KindTuple:
+		vals := make([]EtherType, len(t.Tuple))
+		n := 0
+		for i, ct := range t.Tuple {
+			v, m, err := decodeStatic(ct, data[n:])
+			if err != nil {
+				return nil, 0, err
+			}
+			vals[i] = v
+			n += m
+		}
+		return &Tuple{Types: t.Tuple, Vals: vals}, n, nil
+	}
+	return nil, 0, fmt.Errorf("seth: type %s is not statically sized", t.Sig())
+}
+
+// decodeDynamic decodes a value of a dynamically-sized type whose
+// payload begins at the front of data.
+func decodeDynamic(t Type, data []byte) (EtherType, error) {
+	switch t.Kind {
+	case KindString:
+		b, err := decodeBytesTail(data)
+		if err != nil {
+			return nil, err
+		}
+		s := Str(b)
+		return &s, nil
+	case KindBytes:
+		b, err := decodeBytesTail(data)
+		if err != nil {
+			return nil, err
+		}
+		bs := Bytes(b)
+		return &bs, nil
+	case KindSlice:
+		if len(data) < 32 {
+			return nil, errShortABI
+		}
+		n := new(big.Int).SetBytes(data[:32]).Int64()
+		// Every element occupies at least a 32-byte head slot, so this
+		// bounds the allocation/recursion below to what data could
+		// actually hold, the same way decodeBytesTail bounds n.
+		if n < 0 || n > int64(len(data[32:]))/32 {
+			return nil, errShortABI
+		}
+		vals, err := decodeTuple(repeatType(*t.Elem, int(n)), data[32:])
+		if err != nil {
+			return nil, err
+		}
+		return &Slice{Elem: *t.Elem, Vals: vals}, nil
+	case KindArray:
+		vals, err := decodeTuple(repeatType(*t.Elem, t.Len), data)
+		if err != nil {
+			return nil, err
+		}
+		return &Array{Elem: *t.Elem, Vals: vals}, nil
+	case KindTuple:
+		vals, err := decodeTuple(t.Tuple, data)
+		if err != nil {
+			return nil, err
+		}
+		return &Tuple{Types: t.Tuple, Vals: vals}, nil
+	}
+	return nil, fmt.Errorf("seth: type %s is not dynamically sized", t.Sig())
+}
+
+func decodeBytesTail(data []byte) ([]byte, error) {
+	if len(data) < 32 {
+		return nil, errShortABI
+	}
+	n := new(big.Int).SetBytes(data[:32]).Int64()
+	data = data[32:]
+	if n < 0 || int64(len(data)) < n {
+		return nil, errShortABI
+	}
+	return data[:n], nil
+}
+
+// reflectInto assigns the Go value held by v into the value pointed
+// to by out.
+func reflectInto(v EtherType, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("seth: Unpack output %T is not a non-nil pointer", out)
+	}
+	dst := rv.Elem()
+	// An out of the same pointer type as v receives the value as-is.
+	if rv.Type() == reflect.TypeOf(v) {
+		dst.Set(reflect.ValueOf(v).Elem())
+		return nil
+	}
+	switch x := v.(type) {
+	case *Address:
+		return assign(dst, reflect.ValueOf(*x))
+	case *Int:
+		return assign(dst, reflect.ValueOf(*x))
+	case *Bool:
+		return assign(dst, reflect.ValueOf(bool(*x)))
+	case *Str:
+		return assign(dst, reflect.ValueOf(string(*x)))
+	case *Data:
+		return assign(dst, reflect.ValueOf([]byte(*x)))
+	case *Bytes:
+		return assign(dst, reflect.ValueOf([]byte(*x)))
+	case *Array:
+		return reflectSlice(x.Vals, dst)
+	case *Slice:
+		return reflectSlice(x.Vals, dst)
+	case *Tuple:
+		return reflectTuple(x, dst)
+	}
+	return fmt.Errorf("seth: cannot unpack %T", v)
+}
+
+func assign(dst, src reflect.Value) error {
+	if !src.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("seth: cannot assign %s to %s", src.Type(), dst.Type())
+	}
+	dst.Set(src)
+	return nil
+}
+
+func reflectSlice(elems []EtherType, dst reflect.Value) error {
+	switch dst.Kind() {
+	case reflect.Slice:
+		dst.Set(reflect.MakeSlice(dst.Type(), len(elems), len(elems)))
+	case reflect.Array:
+		if dst.Len() != len(elems) {
+			return fmt.Errorf("seth: array length mismatch: have %d, want %d", len(elems), dst.Len())
+		}
+	default:
+		return fmt.Errorf("seth: cannot unpack array/slice into %s", dst.Type())
+	}
+	for i, e := range elems {
+		if err := reflectInto(e, dst.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reflectTuple(t *Tuple, dst reflect.Value) error {
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("seth: cannot unpack tuple into %s", dst.Type())
+	}
+	if dst.NumField() != len(t.Vals) {
+		return fmt.Errorf("seth: tuple has %d fields, struct has %d", len(t.Vals), dst.NumField())
+	}
+	for i, v := range t.Vals {
+		if err := reflectInto(v, dst.Field(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
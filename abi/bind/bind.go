@@ -0,0 +1,277 @@
+// Package bind generates Go bindings on top of seth.Client/CallOpts
+// from a Solidity ABI JSON file, analogous to go-ethereum's
+// accounts/abi/bind abigen.
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"github.com/newalchemylimited/seth"
+)
+
+// entry is a single element of a Solidity ABI JSON array: a
+// function, event, constructor, fallback, or receive declaration.
+type entry struct {
+	Type            string `json:"type"`
+	Name            string `json:"name"`
+	Inputs          []arg  `json:"inputs"`
+	Outputs         []arg  `json:"outputs"`
+	StateMutability string `json:"stateMutability"`
+	Anonymous       bool   `json:"anonymous"`
+}
+
+// arg is a single function/event argument, possibly a tuple with
+// nested components.
+type arg struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Indexed    bool   `json:"indexed"`
+	Components []arg  `json:"components"`
+}
+
+// sig renders a as a Solidity type string, expanding "tuple"/
+// "tuple[]"/"tuple[3]" into the component form ("(a,b)", "(a,b)[]",
+// "(a,b)[3]") that seth.ParseSig expects.
+func (a arg) sig() string {
+	if !strings.HasPrefix(a.Type, "tuple") {
+		return a.Type
+	}
+	parts := make([]string, len(a.Components))
+	for i, c := range a.Components {
+		parts[i] = c.sig()
+	}
+	return "(" + strings.Join(parts, ",") + ")" + strings.TrimPrefix(a.Type, "tuple")
+}
+
+// parseType resolves a's Solidity type string into a seth.Type.
+func (a arg) parseType() (seth.Type, error) {
+	_, types, err := seth.ParseSig("_(" + a.sig() + ")")
+	if err != nil {
+		return seth.Type{}, err
+	}
+	return types[0], nil
+}
+
+// method is the template-facing view of a function, constructor,
+// fallback or receive entry.
+type method struct {
+	Name   string
+	Const  bool // true for view/pure functions: use ConstCall, not Call
+	Sig    string
+	In     []field
+	Out    []field
+	IsCtor bool
+}
+
+// event is the template-facing view of an event entry.
+type event struct {
+	Name    string
+	Sig     string
+	Indexed []bool
+	All     []field // every argument, in declaration order
+	Filter  []field // the indexed subset of All, in declaration order
+}
+
+// field is a single typed argument or return value, resolved to the
+// Go and seth types the generated code should use for it.
+type field struct {
+	Name    string // Go identifier, e.g. "Amount"
+	GoType  string // e.g. "*seth.Int"
+	NewExpr string // e.g. "new(seth.Int)", used to allocate an out param
+	ABISig  string // e.g. "uint256", the canonical ABI type string
+}
+
+// Generate parses abiJSON (the contents of a Solidity ABI JSON file)
+// and returns the gofmt'd source of a Go file in package pkg binding
+// contractName to seth.Client/CallOpts.
+func Generate(pkg, contractName string, abiJSON []byte) ([]byte, error) {
+	var entries []entry
+	if err := json.Unmarshal(abiJSON, &entries); err != nil {
+		return nil, fmt.Errorf("bind: parsing ABI JSON: %w", err)
+	}
+
+	data := struct {
+		Package  string
+		Contract string
+		Methods  []method
+		Events   []event
+	}{Package: pkg, Contract: contractName}
+
+	for _, e := range entries {
+		switch e.Type {
+		case "function":
+			m, err := newMethod(e)
+			if err != nil {
+				return nil, fmt.Errorf("bind: function %s: %w", e.Name, err)
+			}
+			data.Methods = append(data.Methods, m)
+		case "constructor":
+			m, err := newMethod(entry{Name: "New", Inputs: e.Inputs, StateMutability: e.StateMutability})
+			if err != nil {
+				return nil, fmt.Errorf("bind: constructor: %w", err)
+			}
+			m.IsCtor = true
+			data.Methods = append(data.Methods, m)
+		case "event":
+			ev, err := newEvent(e)
+			if err != nil {
+				return nil, fmt.Errorf("bind: event %s: %w", e.Name, err)
+			}
+			data.Events = append(data.Events, ev)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("bind: executing template: %w", err)
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("bind: formatting generated source: %w\n%s", err, buf.String())
+	}
+	return out, nil
+}
+
+func newMethod(e entry) (method, error) {
+	in := make([]field, len(e.Inputs))
+	for i, a := range e.Inputs {
+		f, err := newField(a, i, "arg")
+		if err != nil {
+			return method{}, err
+		}
+		in[i] = f
+	}
+	out := make([]field, len(e.Outputs))
+	for i, a := range e.Outputs {
+		f, err := newField(a, i, "out")
+		if err != nil {
+			return method{}, err
+		}
+		out[i] = f
+	}
+	return method{
+		Name:  exportedName(e.Name),
+		Const: e.StateMutability == "view" || e.StateMutability == "pure",
+		Sig:   funcSig(e.Name, e.Inputs),
+		In:    in,
+		Out:   out,
+	}, nil
+}
+
+func newEvent(e entry) (event, error) {
+	all := make([]field, len(e.Inputs))
+	indexed := make([]bool, len(e.Inputs))
+	var filter []field
+	for i, a := range e.Inputs {
+		f, err := newField(a, i, "arg")
+		if err != nil {
+			return event{}, err
+		}
+		indexed[i] = a.Indexed
+		if a.Indexed {
+			filter = append(filter, f)
+			// DecodeEvent yields only the topic hash of an indexed
+			// reference-type argument, so the decoded field is a Hash;
+			// the Watch filter keeps the argument's own type.
+			t, err := a.parseType()
+			if err != nil {
+				return event{}, err
+			}
+			if t.IndexedAsHash() {
+				f.GoType, f.NewExpr = "*seth.Hash", "new(seth.Hash)"
+			}
+		}
+		all[i] = f
+	}
+	return event{
+		Name:    exportedName(e.Name),
+		Sig:     funcSig(e.Name, e.Inputs),
+		Indexed: indexed,
+		All:     all,
+		Filter:  filter,
+	}, nil
+}
+
+func newField(a arg, i int, prefix string) (field, error) {
+	t, err := a.parseType()
+	if err != nil {
+		return field{}, err
+	}
+	name := a.Name
+	if name == "" {
+		name = fmt.Sprintf("%s%d", prefix, i)
+	}
+	goType, newExpr, err := goTypeOf(t)
+	if err != nil {
+		return field{}, err
+	}
+	return field{Name: exportedName(name), GoType: goType, NewExpr: newExpr, ABISig: t.Sig()}, nil
+}
+
+// goTypeOf maps a seth.Type to the Go type and allocation expression
+// used for it in generated bindings. Arrays, slices and tuples are
+// passed through as their seth container types (*seth.Array,
+// *seth.Slice, *seth.Tuple) rather than expanded, since callers are
+// expected to build and inspect those directly.
+func goTypeOf(t seth.Type) (goType, newExpr string, err error) {
+	switch t.Kind {
+	case seth.KindAddress:
+		return "*seth.Address", "new(seth.Address)", nil
+	case seth.KindBool:
+		return "*seth.Bool", "new(seth.Bool)", nil
+	case seth.KindUint, seth.KindInt:
+		return "*seth.Int", "new(seth.Int)", nil
+	case seth.KindBytesN:
+		return "*seth.Data", "new(seth.Data)", nil
+	case seth.KindBytes:
+		return "*seth.Bytes", "new(seth.Bytes)", nil
+	case seth.KindString:
+		return "*seth.Str", "new(seth.Str)", nil
+	case seth.KindArray:
+		return "*seth.Array", "new(seth.Array)", nil
+	case seth.KindSlice:
+		return "*seth.Slice", "new(seth.Slice)", nil
+	case seth.KindTuple:
+		return "*seth.Tuple", "new(seth.Tuple)", nil
+	}
+	return "", "", fmt.Errorf("unsupported ABI type %s", t.Sig())
+}
+
+// funcSig renders the canonical "name(type,type,...)" signature seth
+// expects for ABIEncode/EventSig, e.g. "transfer(address,uint256)".
+func funcSig(name string, inputs []arg) string {
+	parts := make([]string, len(inputs))
+	for i, a := range inputs {
+		parts[i] = a.sig()
+	}
+	return name + "(" + strings.Join(parts, ",") + ")"
+}
+
+// exportedName converts an ABI identifier (a Solidity argument or
+// function name) to an exported Go identifier.
+func exportedName(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var tmpl = template.Must(template.New("bind").Funcs(template.FuncMap{
+	"outSig": outSig,
+}).Parse(bindTemplate))
+
+// outSig renders the comma-separated return-type list that the
+// template wraps in parens as the sig argument to seth.Unpack, e.g.
+// "uint256,address" for two return values.
+func outSig(fields []field) string {
+	sigs := make([]string, len(fields))
+	for i, f := range fields {
+		sigs[i] = f.ABISig
+	}
+	return strings.Join(sigs, ",")
+}
@@ -0,0 +1,135 @@
+package bind
+
+// bindTemplate renders a Go source file binding one contract's ABI
+// to seth.Client/CallOpts. It is executed once per Generate call
+// against the {Package, Contract, Methods, Events} data built in
+// bind.go.
+const bindTemplate = `// Code generated by sethbind. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/newalchemylimited/seth"
+)
+
+// {{.Contract}} binds a deployed instance of the contract to a
+// seth.Client for making calls and sending transactions.
+type {{.Contract}} struct {
+	Address seth.Address
+	Client  *seth.Client
+}
+
+// New{{.Contract}} wraps an already-deployed contract at addr.
+func New{{.Contract}}(addr seth.Address, client *seth.Client) *{{.Contract}} {
+	return &{{.Contract}}{Address: addr, Client: client}
+}
+{{range .Methods}}{{if .IsCtor}}
+// Deploy{{$.Contract}} deploys a new {{$.Contract}} contract, appending the
+// ABI-encoded constructor arguments to bytecode, and returns the
+// deployed contract's address (computed from the sender and its
+// current nonce, the same way go-ethereum's CreateAddress does) and
+// the pending transaction's hash. The contract isn't actually usable
+// until the transaction is mined; pass the hash to WaitDeployed to
+// confirm that.
+func Deploy{{$.Contract}}(client *seth.Client, opts *seth.CallOpts, bytecode []byte{{range .In}}, {{.Name}} {{.GoType}}{{end}}) (*seth.Address, seth.Hash, error) {
+	data := append([]byte(nil), bytecode...)
+{{if .In}}	// ABIEncode's 4-byte selector is discarded: constructor
+	// arguments are packed the same way as function arguments, but
+	// with no selector prefix.
+	data = append(data, seth.ABIEncode("{{.Sig}}"{{range .In}}, {{.Name}}{{end}})[4:]...)
+{{end}}	o := *opts
+	o.To = nil
+	o.Data = seth.Data(data)
+	if o.From == nil {
+		return nil, seth.Hash{}, fmt.Errorf("seth: Deploy{{$.Contract}}: opts.From is required to compute the deployed address")
+	}
+	nonce, err := client.TransactionCount(o.From, true)
+	if err != nil {
+		return nil, seth.Hash{}, err
+	}
+	addr := seth.ContractAddress(*o.From, nonce)
+	tx, err := client.Call(&o)
+	if err != nil {
+		return nil, seth.Hash{}, err
+	}
+	return &addr, tx, nil
+}
+{{else if .Const}}
+// {{.Name}} calls the view/pure {{.Sig}} method.
+func (c *{{$.Contract}}) {{.Name}}(caller *seth.Address{{range .In}}, {{.Name}} {{.GoType}}{{end}}) ({{range .Out}}{{.GoType}}, {{end}}error) {
+	opts := &seth.CallOpts{From: caller, To: &c.Address}
+	opts.EncodeCall("{{.Sig}}"{{range .In}}, {{.Name}}{{end}})
+	var raw seth.Data
+	if err := c.Client.ConstCall(opts, &raw, false); err != nil {
+		return {{range .Out}}nil, {{end}}err
+	}
+{{range .Out}}	{{.Name}} := {{.NewExpr}}
+{{end}}	if err := seth.Unpack("({{outSig .Out}})", raw{{range .Out}}, {{.Name}}{{end}}); err != nil {
+		return {{range .Out}}nil, {{end}}err
+	}
+	return {{range .Out}}{{.Name}}, {{end}}nil
+}
+{{else}}
+// {{.Name}} sends a transaction calling the {{.Sig}} method.
+func (c *{{$.Contract}}) {{.Name}}(opts *seth.CallOpts{{range .In}}, {{.Name}} {{.GoType}}{{end}}) (seth.Hash, error) {
+	o := *opts
+	o.To = &c.Address
+	o.EncodeCall("{{.Sig}}"{{range .In}}, {{.Name}}{{end}})
+	return c.Client.Call(&o)
+}
+{{end}}{{end}}
+{{range .Events}}{{$ev := .}}
+// {{$ev.Name}}Event is a decoded {{$ev.Sig}} log.
+type {{$ev.Name}}Event struct {
+{{range .All}}	{{.Name}} {{.GoType}}
+{{end}}}
+
+// Parse{{$ev.Name}} decodes log as a {{$ev.Sig}} event.
+func (c *{{$.Contract}}) Parse{{$ev.Name}}(log *seth.Log) (*{{$ev.Name}}Event, error) {
+{{range .All}}	{{.Name}} := {{.NewExpr}}
+{{end}}	err := seth.DecodeEvent("{{$ev.Sig}}", []bool{ {{range .Indexed}}{{.}}, {{end}} }, log{{range .All}}, {{.Name}}{{end}})
+	if err != nil {
+		return nil, err
+	}
+	return &{{$ev.Name}}Event{ {{range .All}}{{.Name}}: {{.Name}}, {{end}} }, nil
+}
+
+// Watch{{$ev.Name}} subscribes to {{$ev.Sig}} events and delivers each
+// decoded log to ch until ctx is canceled. A nil or empty filter
+// argument matches any value in that position; a non-empty one
+// matches only logs whose indexed argument is one of the given
+// values. It requires the contract's Client to have been
+// constructed with a WSTransport.
+func (c *{{$.Contract}}) Watch{{$ev.Name}}(ctx context.Context, ch chan<- *{{$ev.Name}}Event{{range .Filter}}, {{.Name}} []{{.GoType}}{{end}}) (seth.Subscription, error) {
+	topics := [][]seth.Hash{{"{"}}{seth.HashString("{{$ev.Sig}}")}{{"}"}}
+{{range .Filter}}	if len({{.Name}}) > 0 {
+		t := make([]seth.Hash, len({{.Name}}))
+		for i, v := range {{.Name}} {
+			t[i] = seth.TopicOf(v)
+		}
+		topics = append(topics, t)
+	} else {
+		topics = append(topics, nil)
+	}
+{{end}}	filter := &seth.LogFilter{Address: &c.Address, Topics: topics}
+	raw := make(chan seth.Log)
+	sub, err := c.Client.SubscribeLogs(ctx, filter, raw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for log := range raw {
+			ev, err := c.Parse{{$ev.Name}}(&log)
+			if err != nil {
+				continue
+			}
+			ch <- ev
+		}
+	}()
+	return sub, nil
+}
+{{end}}
+`
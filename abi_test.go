@@ -0,0 +1,70 @@
+package seth
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// TestABIEncodeMixedStaticDynamic guards against the encodeTuple
+// offset bug: a signature mixing a dynamic argument with a static
+// one wider than 32 bytes (a multi-element fixed array, here) used
+// to write a tail offset computed from the argument count rather
+// than the head's actual byte length, corrupting the dynamic
+// argument's payload.
+func TestABIEncodeMixedStaticDynamic(t *testing.T) {
+	i1 := Int(*big.NewInt(1))
+	i2 := Int(*big.NewInt(2))
+	arr := &Array{Elem: Type{Kind: KindUint, Width: 256}, Vals: []EtherType{&i1, &i2}}
+	str := Str("hello")
+
+	enc := ABIEncode("f(uint256[2],string)", arr, &str)
+
+	var outArr [2]Int
+	var outStr string
+	err := Unpack("(uint256[2],string)", Data(enc[4:]), &outArr, &outStr)
+	if err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	if outStr != "hello" {
+		t.Fatalf("string round-tripped as %q, want %q", outStr, "hello")
+	}
+	for i, want := range []int64{1, 2} {
+		if (*big.Int)(&outArr[i]).Int64() != want {
+			t.Fatalf("array element %d = %v, want %v", i, (*big.Int)(&outArr[i]).Int64(), want)
+		}
+	}
+}
+
+// TestABIEncodeDecodeRoundTrip exercises a basic static/dynamic
+// mix: an address, a dynamic bytes value, and a plain integer.
+func TestABIEncodeDecodeRoundTrip(t *testing.T) {
+	addr := Address{1, 2, 3}
+	data := Bytes([]byte("some data"))
+	amount := Int(*big.NewInt(42))
+
+	enc := ABIEncode("transfer(address,bytes,uint256)", &addr, &data, &amount)
+	if len(enc) < 4 {
+		t.Fatalf("encoded call shorter than a selector: %d bytes", len(enc))
+	}
+
+	vals, err := ABIDecode("(address,bytes,uint256)", enc[4:])
+	if err != nil {
+		t.Fatalf("ABIDecode: %v", err)
+	}
+	if len(vals) != 3 {
+		t.Fatalf("got %d values, want 3", len(vals))
+	}
+	gotAddr, ok := vals[0].(*Address)
+	if !ok || !reflect.DeepEqual(*gotAddr, addr) {
+		t.Fatalf("address round-tripped as %v, want %v", vals[0], addr)
+	}
+	gotData, ok := vals[1].(*Bytes)
+	if !ok || !reflect.DeepEqual([]byte(*gotData), []byte(data)) {
+		t.Fatalf("bytes round-tripped as %v, want %v", vals[1], data)
+	}
+	gotInt, ok := vals[2].(*Int)
+	if !ok || (*big.Int)(gotInt).Int64() != 42 {
+		t.Fatalf("int round-tripped as %v, want 42", vals[2])
+	}
+}
@@ -0,0 +1,53 @@
+// Command sethbind generates a Go contract binding from a Solidity
+// ABI JSON file, analogous to go-ethereum's abigen.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/newalchemylimited/seth/abi/bind"
+)
+
+func main() {
+	var (
+		pkg  = flag.String("pkg", "main", "package name for the generated file")
+		name = flag.String("type", "", "generated contract type name, e.g. ERC20")
+		out  = flag.String("out", "", "output file (defaults to stdout)")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: sethbind -type Name [-pkg pkg] [-out file.go] abi.json\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *name == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	abiJSON, err := ioutil.ReadFile(flag.Arg(0))
+	if err != nil {
+		fatalf("reading ABI: %s\n", err)
+	}
+
+	src, err := bind.Generate(*pkg, *name, abiJSON)
+	if err != nil {
+		fatalf("generating binding: %s\n", err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		fatalf("writing %s: %s\n", *out, err)
+	}
+}
+
+func fatalf(f string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, f, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,318 @@
+package seth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Log is a single Ethereum event log entry, as returned by
+// eth_getLogs, eth_getFilterChanges, and the "logs" eth_subscribe
+// notification stream.
+type Log struct {
+	Address     Address `json:"address"`
+	Topics      []Hash  `json:"topics"`
+	Data        Data    `json:"data"`
+	BlockNumber Int     `json:"blockNumber"`
+	BlockHash   Hash    `json:"blockHash"`
+	TxHash      Hash    `json:"transactionHash"`
+	TxIndex     Int     `json:"transactionIndex"`
+	LogIndex    Int     `json:"logIndex"`
+	Removed     bool    `json:"removed"`
+}
+
+// LogFilter describes criteria for matching event logs, as accepted
+// by eth_getLogs, eth_newFilter, and eth_subscribe("logs", ...).
+// A nil Address or Topics entry matches any value; Topics[i] being
+// a list matches any topic in that list at position i.
+type LogFilter struct {
+	Address   *Address
+	FromBlock int64 // defaults to the earliest block, like eth_getLogs
+	ToBlock   int64 // defaults to the latest block, like eth_getLogs
+	Topics    [][]Hash
+}
+
+// MarshalJSON implements json.Marshaler, translating FromBlock and
+// ToBlock into the "latest"/"pending" tag or hex quantity that
+// eth_getLogs expects.
+func (f *LogFilter) MarshalJSON() ([]byte, error) {
+	v := struct {
+		Address   *Address        `json:"address,omitempty"`
+		FromBlock json.RawMessage `json:"fromBlock,omitempty"`
+		ToBlock   json.RawMessage `json:"toBlock,omitempty"`
+		Topics    [][]Hash        `json:"topics,omitempty"`
+	}{Address: f.Address, Topics: f.Topics}
+	if f.FromBlock != 0 {
+		v.FromBlock = blockTag(f.FromBlock)
+	}
+	if f.ToBlock != 0 {
+		v.ToBlock = blockTag(f.ToBlock)
+	}
+	return json.Marshal(v)
+}
+
+func blockTag(n int64) json.RawMessage {
+	switch n {
+	case Pending:
+		return rawpending
+	case Latest:
+		return rawlatest
+	default:
+		return json.RawMessage(fmt.Sprintf(`"0x%x"`, n))
+	}
+}
+
+// GetLogs returns all logs matching the given filter.
+func (c *Client) GetLogs(filter *LogFilter) ([]Log, error) {
+	buf, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	var out []Log
+	err = c.do("eth_getLogs", []json.RawMessage{buf}, &out)
+	return out, err
+}
+
+// NewFilter installs a log filter on the node and returns its id,
+// for later use with GetFilterChanges and UninstallFilter.
+func (c *Client) NewFilter(filter *LogFilter) (string, error) {
+	buf, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+	var id string
+	err = c.do("eth_newFilter", []json.RawMessage{buf}, &id)
+	return id, err
+}
+
+// GetFilterChanges returns the logs accumulated since the last poll
+// of the filter with the given id.
+func (c *Client) GetFilterChanges(id string) ([]Log, error) {
+	buf, _ := json.Marshal(id)
+	var out []Log
+	err := c.do("eth_getFilterChanges", []json.RawMessage{buf}, &out)
+	return out, err
+}
+
+// UninstallFilter removes a filter installed with NewFilter.
+func (c *Client) UninstallFilter(id string) (bool, error) {
+	buf, _ := json.Marshal(id)
+	var ok bool
+	err := c.do("eth_uninstallFilter", []json.RawMessage{buf}, &ok)
+	return ok, err
+}
+
+// Subscription represents an open eth_subscribe subscription, as
+// returned by Client.SubscribeLogs.
+type Subscription interface {
+	// Unsubscribe sends eth_unsubscribe and stops delivery to the
+	// channel given to SubscribeLogs.
+	Unsubscribe()
+
+	// Err returns a channel that receives the subscription's
+	// terminal error, if any, and is closed when it ends.
+	Err() <-chan error
+}
+
+// SubscribeLogs opens an eth_subscribe("logs", filter) subscription
+// and delivers matching logs to ch until the context is canceled or
+// the subscription is unsubscribed. It requires the client to have
+// been constructed with a WSTransport, since only a persistent
+// connection can carry subscription notifications.
+func (c *Client) SubscribeLogs(ctx context.Context, filter *LogFilter, ch chan<- Log) (Subscription, error) {
+	ws, ok := c.t.(*WSTransport)
+	if !ok {
+		return nil, fmt.Errorf("seth: SubscribeLogs requires a WSTransport, got %T", c.t)
+	}
+	buf, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+	notify, unsub, err := ws.subscribe(ctx, "logs", []json.RawMessage{[]byte(`"logs"`), buf})
+	if err != nil {
+		return nil, err
+	}
+	sub := &logSubscription{unsub: unsub, errc: make(chan error, 1)}
+	go sub.run(notify, ch)
+	return sub, nil
+}
+
+type logSubscription struct {
+	unsub func()
+	errc  chan error
+}
+
+func (s *logSubscription) run(notify <-chan json.RawMessage, ch chan<- Log) {
+	defer close(s.errc)
+	for raw := range notify {
+		var l Log
+		if err := json.Unmarshal(raw, &l); err != nil {
+			s.errc <- err
+			return
+		}
+		ch <- l
+	}
+}
+
+func (s *logSubscription) Unsubscribe()      { s.unsub() }
+func (s *logSubscription) Err() <-chan error { return s.errc }
+
+// EventSig computes the topic0 of an event with the given name and
+// argument types, e.g. EventSig("Transfer", "address", "address",
+// "uint256") for Solidity's "event Transfer(address,address,uint256)".
+func EventSig(name string, argTypes ...string) Hash {
+	return HashString(name + "(" + joinTypes(argTypes) + ")")
+}
+
+func joinTypes(types []string) string {
+	out := ""
+	for i, t := range types {
+		if i > 0 {
+			out += ","
+		}
+		out += t
+	}
+	return out
+}
+
+// DecodeEvent decodes a log emitted by an event with the given
+// signature (e.g. "Transfer(address,address,uint256)") and indexed
+// flags, one per argument, into out. Indexed arguments are read from
+// log.Topics[1:] (hashed, per Solidity rules, if their type is a
+// reference type rather than a value type, per Type.IndexedAsHash);
+// the remaining arguments are ABI-decoded from log.Data.
+func DecodeEvent(sig string, indexed []bool, log *Log, out ...EtherType) error {
+	_, types, err := ParseSig(sig)
+	if err != nil {
+		return err
+	}
+	if len(types) != len(indexed) {
+		return fmt.Errorf("seth: %d argument types, %d indexed flags", len(types), len(indexed))
+	}
+	if len(types) != len(out) {
+		return fmt.Errorf("seth: %d event arguments, %d outputs given", len(types), len(out))
+	}
+
+	var dataTypes []Type
+	var dataOut []EtherType
+	topic := 1
+	for i, t := range types {
+		if !indexed[i] {
+			dataTypes = append(dataTypes, t)
+			dataOut = append(dataOut, out[i])
+			continue
+		}
+		if topic >= len(log.Topics) {
+			return fmt.Errorf("seth: log has %d topics, need at least %d", len(log.Topics), topic+1)
+		}
+		if t.IndexedAsHash() {
+			// Solidity stores only the keccak256 hash of a reference-
+			// type indexed argument; the original value isn't
+			// recoverable.
+			h, ok := out[i].(*Hash)
+			if !ok {
+				return fmt.Errorf("seth: indexed dynamic argument %d must be decoded into a *Hash", i)
+			}
+			*h = log.Topics[topic]
+		} else {
+			v, _, err := decodeStatic(t, log.Topics[topic][:])
+			if err != nil {
+				return err
+			}
+			if err := reflectIntoExisting(v, out[i]); err != nil {
+				return err
+			}
+		}
+		topic++
+	}
+
+	vals, err := decodeTuple(dataTypes, log.Data)
+	if err != nil {
+		return err
+	}
+	for i, v := range vals {
+		if err := reflectIntoExisting(v, dataOut[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TopicOf computes the topic value Solidity records for an indexed
+// event argument holding v: the ABI-encoded word itself for a value
+// type (address, bool, an integer type, or fixed-size bytesN), the
+// keccak256 hash of the raw contents for a string or dynamic bytes,
+// or the keccak256 hash of v.EncodeABI for an array, slice, or
+// tuple, mirroring the same rule
+// DecodeEvent applies in reverse via Type.IndexedAsHash. Generated
+// Watch<Event> bindings use it to build LogFilter.Topics from the
+// values callers want to filter on.
+func TopicOf(v EtherType) Hash {
+	switch x := v.(type) {
+	case *Str:
+		return Keccak256([]byte(*x))
+	case *Bytes:
+		return Keccak256([]byte(*x))
+	case *Address, *Bool, *Int, *Data:
+		var h Hash
+		copy(h[:], v.EncodeABI(nil))
+		return h
+	default:
+		// Array, Slice, and Tuple: Solidity indexes these (and any
+		// other reference type) by the keccak256 hash of their full
+		// ABI encoding, not just the raw value.
+		return Keccak256(v.EncodeABI(nil))
+	}
+}
+
+// reflectIntoExisting copies the decoded value v into the
+// EtherType-typed out argument supplied by the caller, which must be
+// a pointer to the same concrete type ABIDecode would have produced.
+func reflectIntoExisting(v, out EtherType) error {
+	switch x := v.(type) {
+	case *Address:
+		o, ok := out.(*Address)
+		if !ok {
+			return typeErr(Type{Kind: KindAddress}, out)
+		}
+		*o = *x
+	case *Int:
+		o, ok := out.(*Int)
+		if !ok {
+			return fmt.Errorf("seth: cannot decode into %T, want *Int", out)
+		}
+		*o = *x
+	case *Bool:
+		o, ok := out.(*Bool)
+		if !ok {
+			return fmt.Errorf("seth: cannot decode into %T, want *Bool", out)
+		}
+		*o = *x
+	case *Str:
+		o, ok := out.(*Str)
+		if !ok {
+			return fmt.Errorf("seth: cannot decode into %T, want *Str", out)
+		}
+		*o = *x
+	case *Bytes:
+		o, ok := out.(*Bytes)
+		if !ok {
+			return fmt.Errorf("seth: cannot decode into %T, want *Bytes", out)
+		}
+		*o = *x
+	case *Data:
+		o, ok := out.(*Data)
+		if !ok {
+			return fmt.Errorf("seth: cannot decode into %T, want *Data", out)
+		}
+		*o = *x
+	default:
+		// Array, Slice, and Tuple: out must have v's own pointer type.
+		if reflect.TypeOf(out) != reflect.TypeOf(v) {
+			return fmt.Errorf("seth: DecodeEvent does not support destination type %T", out)
+		}
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(v).Elem())
+	}
+	return nil
+}
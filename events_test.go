@@ -0,0 +1,105 @@
+package seth
+
+import (
+	"math/big"
+	"testing"
+
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestTopicOfValueType checks that TopicOf stores a value-type
+// (address, bool, integer, fixed-size bytesN) indexed argument as
+// its plain ABI encoding, not a hash of it.
+func TestTopicOfValueType(t *testing.T) {
+	amount := Int(*big.NewInt(42))
+	got := TopicOf(&amount)
+
+	var want Hash
+	copy(want[:], amount.EncodeABI(nil))
+	if got != want {
+		t.Fatalf("TopicOf(uint256) = %x, want %x", got, want)
+	}
+}
+
+// TestTopicOfReferenceTypeHashesFullEncoding checks that TopicOf
+// hashes the full ABI encoding of a reference-type indexed argument
+// (here, a fixed-size array) instead of truncating it to the first
+// 32 bytes of the encoding, as Solidity requires for any indexed
+// array, tuple, string, or dynamic bytes argument.
+func TestTopicOfReferenceTypeHashesFullEncoding(t *testing.T) {
+	elemType := Type{Kind: KindBytesN, Width: 32}
+	v0 := Data(make([]byte, 32))
+	v1 := Data(make([]byte, 32))
+	v0[0], v1[0] = 0xaa, 0xbb
+	arr := &Array{Elem: elemType, Vals: []EtherType{&v0, &v1}}
+
+	got := TopicOf(arr)
+
+	want := Hash(gethcrypto.Keccak256Hash(arr.EncodeABI(nil)))
+	if got != want {
+		t.Fatalf("TopicOf(bytes32[2]) = %x, want keccak256 of the full encoding %x", got, want)
+	}
+	var truncated Hash
+	copy(truncated[:], arr.EncodeABI(nil))
+	if got == truncated {
+		t.Fatalf("TopicOf(bytes32[2]) = %x, looks like a truncated raw encoding, not a hash", got)
+	}
+}
+
+// TestDecodeEventIndexedReferenceType checks that DecodeEvent reads
+// an indexed array argument as the keccak256 hash Solidity actually
+// records, the mirror image of TestTopicOfReferenceTypeHashesFullEncoding.
+func TestDecodeEventIndexedReferenceType(t *testing.T) {
+	elemType := Type{Kind: KindBytesN, Width: 32}
+	v0 := Data(make([]byte, 32))
+	v1 := Data(make([]byte, 32))
+	v0[0], v1[0] = 0xaa, 0xbb
+	arr := &Array{Elem: elemType, Vals: []EtherType{&v0, &v1}}
+	amount := Int(*big.NewInt(7))
+
+	log := &Log{
+		Topics: []Hash{
+			EventSig("Foo", "bytes32[2]", "uint256"),
+			TopicOf(arr),
+		},
+		Data: Data(ABIEncode("f(uint256)", &amount)[4:]),
+	}
+
+	var gotHash Hash
+	var gotAmount Int
+	err := DecodeEvent("Foo(bytes32[2],uint256)", []bool{true, false}, log, &gotHash, &gotAmount)
+	if err != nil {
+		t.Fatalf("DecodeEvent: %v", err)
+	}
+	if gotHash != log.Topics[1] {
+		t.Fatalf("indexed array decoded as %x, want the raw topic hash %x", gotHash, log.Topics[1])
+	}
+	if (*big.Int)(&gotAmount).Int64() != 7 {
+		t.Fatalf("non-indexed amount = %v, want 7", (*big.Int)(&gotAmount))
+	}
+}
+
+// TestDecodeEventIndexedValueType checks that DecodeEvent reads an
+// indexed value-type argument (here, an address) directly out of
+// its topic rather than treating it as a hash.
+func TestDecodeEventIndexedValueType(t *testing.T) {
+	from := Address{1, 2, 3}
+	var topic Hash
+	copy(topic[12:], from[:])
+
+	log := &Log{
+		Topics: []Hash{
+			EventSig("Transfer", "address"),
+			topic,
+		},
+		Data: Data{},
+	}
+
+	var got Address
+	if err := DecodeEvent("Transfer(address)", []bool{true}, log, &got); err != nil {
+		t.Fatalf("DecodeEvent: %v", err)
+	}
+	if got != from {
+		t.Fatalf("indexed address decoded as %x, want %x", got, from)
+	}
+}
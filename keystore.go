@@ -0,0 +1,208 @@
+package seth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Signer signs transaction hashes on behalf of an Ethereum account,
+// letting Client.SignAndSend submit transactions to a node that
+// hasn't unlocked (or, e.g. Infura, can never unlock) the sender's
+// key.
+type Signer interface {
+	// Address is the account this signer signs on behalf of.
+	Address() Address
+
+	// SignHash returns a 65-byte recoverable ECDSA signature (r and
+	// s as 32-byte big-endian words, followed by a recovery id of 0
+	// or 1) over hash.
+	SignHash(hash Hash) ([65]byte, error)
+}
+
+// PrivateKeySigner is a Signer backed by a raw ECDSA private key.
+type PrivateKeySigner struct {
+	Key  *ecdsa.PrivateKey
+	addr Address
+}
+
+// NewPrivateKeySigner wraps key, deriving its Ethereum address.
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{Key: key, addr: pubkeyToAddress(&key.PublicKey)}
+}
+
+// Address implements Signer.
+func (s *PrivateKeySigner) Address() Address { return s.addr }
+
+// SignHash implements Signer.
+func (s *PrivateKeySigner) SignHash(hash Hash) ([65]byte, error) {
+	var out [65]byte
+	sig, err := crypto.Sign(hash[:], s.Key)
+	if err != nil {
+		return out, err
+	}
+	copy(out[:], sig)
+	return out, nil
+}
+
+func pubkeyToAddress(pub *ecdsa.PublicKey) Address {
+	var raw [64]byte
+	pub.X.FillBytes(raw[:32])
+	pub.Y.FillBytes(raw[32:])
+	h := Keccak256(raw[:])
+	var addr Address
+	copy(addr[:], h[12:])
+	return addr
+}
+
+// KeyStore holds Web3 Secret Storage v3 keyfiles
+// (https://github.com/ethereum/wiki/wiki/Web3-Secret-Storage-Definition),
+// the same JSON format geth's account keystore and most wallet
+// exports use, as their raw encrypted JSON; a keyfile is decrypted
+// only when Unlock is called for its address.
+type KeyStore struct {
+	mu    sync.Mutex
+	files map[Address][]byte // raw keyfile JSON, by address
+}
+
+// NewKeyStore returns an empty KeyStore.
+func NewKeyStore() *KeyStore {
+	return &KeyStore{files: make(map[Address][]byte)}
+}
+
+// AddFile registers a keyfile's raw JSON contents, returning the
+// account address it names.
+func (ks *KeyStore) AddFile(data []byte) (Address, error) {
+	var kj keyfileJSON
+	if err := json.Unmarshal(data, &kj); err != nil {
+		return Address{}, fmt.Errorf("seth: parsing keyfile: %w", err)
+	}
+	raw, err := hex.DecodeString(kj.Address)
+	if err != nil || len(raw) != 20 {
+		return Address{}, fmt.Errorf("seth: bad keyfile address %q", kj.Address)
+	}
+	var addr Address
+	copy(addr[:], raw)
+
+	ks.mu.Lock()
+	ks.files[addr] = data
+	ks.mu.Unlock()
+	return addr, nil
+}
+
+// LoadFile reads and registers the keyfile at path, as AddFile.
+func (ks *KeyStore) LoadFile(path string) (Address, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Address{}, err
+	}
+	return ks.AddFile(data)
+}
+
+// Unlock decrypts the keyfile registered for addr with passphrase
+// and returns a Signer for it.
+func (ks *KeyStore) Unlock(addr Address, passphrase string) (Signer, error) {
+	ks.mu.Lock()
+	data, ok := ks.files[addr]
+	ks.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("seth: no keyfile loaded for %x", addr[:])
+	}
+	key, err := decryptKeyfile(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+// keyfileJSON is the subset of the Web3 Secret Storage v3 format
+// this package understands: scrypt key derivation and AES-128-CTR
+// encryption, which is what geth produces by default.
+type keyfileJSON struct {
+	Address string `json:"address"`
+	Crypto  struct {
+		Cipher       string `json:"cipher"`
+		CipherText   string `json:"ciphertext"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		KDF       string `json:"kdf"`
+		KDFParams struct {
+			N     int    `json:"n"`
+			R     int    `json:"r"`
+			P     int    `json:"p"`
+			DKLen int    `json:"dklen"`
+			Salt  string `json:"salt"`
+		} `json:"kdfparams"`
+		MAC string `json:"mac"`
+	} `json:"crypto"`
+	Version int `json:"version"`
+}
+
+func decryptKeyfile(data []byte, passphrase string) (*ecdsa.PrivateKey, error) {
+	var kj keyfileJSON
+	if err := json.Unmarshal(data, &kj); err != nil {
+		return nil, fmt.Errorf("seth: parsing keyfile: %w", err)
+	}
+	if kj.Version != 3 {
+		return nil, fmt.Errorf("seth: unsupported keystore version %d", kj.Version)
+	}
+	if kj.Crypto.KDF != "scrypt" {
+		return nil, fmt.Errorf("seth: unsupported KDF %q", kj.Crypto.KDF)
+	}
+	if kj.Crypto.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("seth: unsupported cipher %q", kj.Crypto.Cipher)
+	}
+
+	if kj.Crypto.KDFParams.DKLen < 32 {
+		return nil, fmt.Errorf("seth: keyfile dklen %d too short", kj.Crypto.KDFParams.DKLen)
+	}
+	salt, err := hex.DecodeString(kj.Crypto.KDFParams.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("seth: bad keyfile salt: %w", err)
+	}
+	derivedKey, err := scrypt.Key([]byte(passphrase), salt,
+		kj.Crypto.KDFParams.N, kj.Crypto.KDFParams.R, kj.Crypto.KDFParams.P, kj.Crypto.KDFParams.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("seth: deriving keyfile key: %w", err)
+	}
+
+	cipherText, err := hex.DecodeString(kj.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("seth: bad keyfile ciphertext: %w", err)
+	}
+	wantMAC, err := hex.DecodeString(kj.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("seth: bad keyfile mac: %w", err)
+	}
+	mac := Keccak256(append(append([]byte{}, derivedKey[16:32]...), cipherText...))
+	if !hmac.Equal(mac[:], wantMAC) {
+		return nil, errors.New("seth: keyfile MAC mismatch: wrong passphrase?")
+	}
+
+	iv, err := hex.DecodeString(kj.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("seth: bad keyfile iv: %w", err)
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("seth: keyfile iv is %d bytes, want %d", len(iv), aes.BlockSize)
+	}
+	block, err := aes.NewCipher(derivedKey[:16])
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(cipherText))
+	cipher.NewCTR(block, iv).XORKeyStream(plain, cipherText)
+
+	return crypto.ToECDSA(plain)
+}
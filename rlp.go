@@ -0,0 +1,45 @@
+package seth
+
+import "math/big"
+
+// This file implements just enough RLP encoding to build a signed
+// transaction envelope; it's not a general-purpose RLP codec.
+
+// rlpBytes encodes b as an RLP string.
+func rlpBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return b
+	}
+	return append(rlpHeader(len(b), 0x80, 0xb7), b...)
+}
+
+// rlpBig encodes n as an RLP string holding its big-endian bytes,
+// with a nil or zero n encoding as the empty string (as go-ethereum
+// does for unset transaction fields).
+func rlpBig(n *big.Int) []byte {
+	if n == nil || n.Sign() == 0 {
+		return []byte{0x80}
+	}
+	return rlpBytes(n.Bytes())
+}
+
+// rlpList encodes items, each already RLP-encoded, as an RLP list.
+func rlpList(items ...[]byte) []byte {
+	var body []byte
+	for _, it := range items {
+		body = append(body, it...)
+	}
+	return append(rlpHeader(len(body), 0xc0, 0xf7), body...)
+}
+
+// rlpHeader returns the length header for an RLP string or list of
+// the given payload size: shortTag+size for payloads up to 55 bytes,
+// otherwise longTag+len(size bytes) followed by size's big-endian
+// bytes.
+func rlpHeader(size int, shortTag, longTag byte) []byte {
+	if size <= 55 {
+		return []byte{shortTag + byte(size)}
+	}
+	n := big.NewInt(int64(size)).Bytes()
+	return append([]byte{longTag + byte(len(n))}, n...)
+}
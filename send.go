@@ -3,9 +3,7 @@ package seth
 import (
 	"encoding/binary"
 	"encoding/json"
-	"fmt"
 	"math/big"
-	"strings"
 )
 
 // EtherType represents a type in the
@@ -109,85 +107,17 @@ type CallOpts struct {
 	GasPrice *Int     `json:"gasPrice,omitempty"` // GasPrice offered for gas
 	Value    *Int     `json:"value,omitempty"`    // Value to send
 	Data     Data     `json:"data"`               // Input to the call
-}
-
-const illegal = " \t\n\b-+/~!@#$%^&*=|;:\"<>\\?"
 
-// check that the given arguments correspond
-// to the arguments given in the function signature 'f'
-// where 'f' is of the form
-//  name(type0,type1,type2)
-func typecheck(f string, args []EtherType) {
-	if strings.ContainsAny(f, illegal) {
-		panic("illegal characters in function signature string")
-	}
-	lparen := strings.IndexByte(f, '(')
-	if lparen == -1 {
-		panic(f + " has no left paren")
-	}
-	rparen := strings.IndexByte(f, ')')
-	if rparen != len(f)-1 {
-		panic(f + " has a bad right paren")
-	}
-	var argstrings []string
-	if strings.Contains(f[lparen+1:rparen], ",") {
-		argstrings = strings.Split(f[lparen+1:rparen], ",")
-		if len(argstrings) != len(args) {
-			panic(fmt.Sprintf("mismatched argument lists: %d args vs %d given", len(argstrings), len(args)))
-		}
-	}
-	for i := range argstrings {
-		switch argstrings[i] {
-		case "address":
-			if _, ok := args[i].(*Address); !ok {
-				panic("address argument not an address")
-			}
-		case "uint", "uint256", "int", "int256":
-			if _, ok := args[i].(*Int); !ok {
-				panic(argstrings[i] + " argument not an Int")
-			}
-		case "bytes32":
-			if _, ok := args[i].(*Data); !ok {
-				if _, ok = args[i].(*Int); !ok {
-					panic(argstrings[i] + "argument not an Int or Data")
-				}
-			}
-		default:
-			if strings.HasSuffix(argstrings[i], "[]") {
-				if _, ok := args[i].(EtherSlice); !ok {
-					panic("argument not a slice")
-				}
-			}
-			// TODO: more typechecking
-		}
-	}
-}
-
-// ABIEncode encodes a function and its arguments
-func ABIEncode(fn string, args ...EtherType) []byte {
-	typecheck(fn, args)
-
-	buf := make([]byte, 4, 4+len(args)*32)
-	fhash := HashString(fn)
-	copy(buf[:4], fhash[:4])
-
-	var dyn []EtherSlice
-	dynoff := uint(len(args)) * 32
-	for _, a := range args {
-		if es, ok := a.(EtherSlice); ok {
-			// just encode the dynamic argument offset
-			buf = padint(dynoff, buf)
-			dyn = append(dyn, es)
-			dynoff += 32
-			continue
-		}
-		buf = a.EncodeABI(buf)
-	}
-	for i := range dyn {
-		buf = padint(uint(dyn[i].Len()), buf)
-		buf = dyn[i].EncodeABI(buf)
-	}
-	return buf
+	// Nonce, MaxFeePerGas and MaxPriorityFeePerGas go into the raw
+	// transaction Client.SignAndSend builds; Call, EstimateGas and
+	// ConstCall pass them to the node in the JSON object like any
+	// other field. SignAndSend fills in Nonce when it is nil, but
+	// never MaxFeePerGas or MaxPriorityFeePerGas. Setting
+	// MaxFeePerGas selects an EIP-1559 (type-2) transaction instead
+	// of a legacy one.
+	Nonce                *Int `json:"nonce,omitempty"`
+	MaxFeePerGas         *Int `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *Int `json:"maxPriorityFeePerGas,omitempty"`
 }
 
 // EncodeCall sets up c.Data so that it reflects
@@ -246,4 +176,32 @@ func (c *Client) StorageAt(addr *Address, offset *Hash, block int64) (Hash, erro
 	var out Hash
 	err := c.do("eth_getStorageAt", []json.RawMessage{buf, buf2, buf3}, &out)
 	return out, err
-}
\ No newline at end of file
+}
+
+// TransactionCount returns the number of transactions sent from
+// addr, used as its next nonce. If pending is true, transactions in
+// the pending block are counted too.
+func (c *Client) TransactionCount(addr *Address, pending bool) (Int, error) {
+	buf, _ := json.Marshal(addr)
+	tag := rawlatest
+	if pending {
+		tag = rawpending
+	}
+	var n Int
+	err := c.do("eth_getTransactionCount", []json.RawMessage{buf, tag}, &n)
+	return n, err
+}
+
+// GasPrice returns the node's current suggested gas price.
+func (c *Client) GasPrice() (Int, error) {
+	var gp Int
+	err := c.do("eth_gasPrice", nil, &gp)
+	return gp, err
+}
+
+// ChainID returns the chain id used for EIP-155 replay protection.
+func (c *Client) ChainID() (Int, error) {
+	var id Int
+	err := c.do("eth_chainId", nil, &id)
+	return id, err
+}
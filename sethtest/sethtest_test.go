@@ -0,0 +1,128 @@
+package sethtest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/newalchemylimited/seth"
+)
+
+// returns42 is the runtime bytecode for a contract with no functions
+// of its own: it just MSTOREs 42 and RETURNs it, regardless of
+// calldata. Handy for exercising ConstCall/ABIDecode without needing
+// a real Solidity toolchain.
+//
+//	PUSH1 0x2a PUSH1 0x00 MSTORE PUSH1 0x20 PUSH1 0x00 RETURN
+var returns42 = []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3}
+
+// TestSimulatedClientConstCall checks that ConstCall against the
+// in-process EVM returns data ABIDecode can parse, backstopping both
+// the EVM wiring in backend.execute and the ABI codec it feeds.
+func TestSimulatedClientConstCall(t *testing.T) {
+	addr := seth.Address{0x42}
+	client := NewClient(GenesisAlloc{addr: {Code: returns42}})
+
+	opts := &seth.CallOpts{To: &addr}
+	var out seth.Data
+	if err := client.ConstCall(opts, &out, false); err != nil {
+		t.Fatalf("ConstCall: %v", err)
+	}
+
+	vals, err := seth.ABIDecode("(uint256)", out)
+	if err != nil {
+		t.Fatalf("ABIDecode: %v", err)
+	}
+	got, ok := vals[0].(*seth.Int)
+	if !ok {
+		t.Fatalf("decoded %T, want *seth.Int", vals[0])
+	}
+	if (*big.Int)(got).Int64() != 42 {
+		t.Fatalf("result = %v, want 42", (*big.Int)(got))
+	}
+
+	gas, err := client.EstimateGas(opts)
+	if err != nil {
+		t.Fatalf("EstimateGas: %v", err)
+	}
+	if (*big.Int)(&gas).Sign() <= 0 {
+		t.Fatalf("estimated gas = %v, want > 0", (*big.Int)(&gas))
+	}
+}
+
+// TestSimulatedClientCommitRollback checks that Rollback discards a
+// sent transaction's effect on the pending state and Commit makes it
+// permanent, using the sender's nonce (bumped by contract creation)
+// as an observable side effect.
+func TestSimulatedClientCommitRollback(t *testing.T) {
+	deployer := seth.Address{0x01}
+	client := NewClient(GenesisAlloc{deployer: {Balance: big.NewInt(1e18)}})
+
+	opts := &seth.CallOpts{From: &deployer, Data: seth.Data(returns42)}
+
+	if _, err := client.Call(opts); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if n, err := client.TransactionCount(&deployer, true); err != nil || (*big.Int)(&n).Int64() != 1 {
+		t.Fatalf("pending nonce = %v, %v, want 1, nil", n, err)
+	}
+	if n, err := client.TransactionCount(&deployer, false); err != nil || (*big.Int)(&n).Int64() != 0 {
+		t.Fatalf("committed nonce = %v, %v, want 0, nil", n, err)
+	}
+
+	client.Rollback()
+	if n, err := client.TransactionCount(&deployer, true); err != nil || (*big.Int)(&n).Int64() != 0 {
+		t.Fatalf("pending nonce after Rollback = %v, %v, want 0, nil", n, err)
+	}
+
+	tx, err := client.Call(opts)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	client.Commit()
+	if n, err := client.TransactionCount(&deployer, false); err != nil || (*big.Int)(&n).Int64() != 1 {
+		t.Fatalf("committed nonce after Commit = %v, %v, want 1, nil", n, err)
+	}
+
+	r, err := client.TransactionReceipt(tx)
+	if err != nil {
+		t.Fatalf("TransactionReceipt: %v", err)
+	}
+	if r == nil || r.ContractAddress == nil {
+		t.Fatalf("receipt = %+v, want a mined receipt naming a contract address", r)
+	}
+}
+
+// TestSimulatedClientPlainCallBumpsNonce checks that a plain message
+// call (opts.To set) advances the sender's nonce just like a
+// contract creation does, and that two such calls before a Commit
+// get distinct transaction hashes rather than overwriting each
+// other's pending receipt.
+func TestSimulatedClientPlainCallBumpsNonce(t *testing.T) {
+	sender := seth.Address{0x01}
+	target := seth.Address{0x42}
+	client := NewClient(GenesisAlloc{
+		sender: {Balance: big.NewInt(1e18)},
+		target: {Code: returns42},
+	})
+
+	opts := &seth.CallOpts{From: &sender, To: &target}
+
+	tx1, err := client.Call(opts)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if n, err := client.TransactionCount(&sender, true); err != nil || (*big.Int)(&n).Int64() != 1 {
+		t.Fatalf("pending nonce after first Call = %v, %v, want 1, nil", n, err)
+	}
+
+	tx2, err := client.Call(opts)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if n, err := client.TransactionCount(&sender, true); err != nil || (*big.Int)(&n).Int64() != 2 {
+		t.Fatalf("pending nonce after second Call = %v, %v, want 2, nil", n, err)
+	}
+	if tx1 == tx2 {
+		t.Fatalf("two identical-calldata Calls synthesized the same tx hash %v", tx1)
+	}
+}
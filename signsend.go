@@ -0,0 +1,175 @@
+package seth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// SignAndSend signs opts with signer and submits the resulting raw
+// transaction via eth_sendRawTransaction, rather than forwarding
+// opts to eth_sendTransaction as Client.Call does. This works
+// against any node, including read-only endpoints like Infura that
+// have no unlocked accounts.
+//
+// Nonce is filled in from TransactionCount if unset, Gas from
+// EstimateGas, and GasPrice from GasPrice; the chain id for EIP-155
+// replay protection is always fetched fresh via ChainID. If
+// opts.MaxFeePerGas is set, the transaction is signed and sent as an
+// EIP-1559 (type-2) transaction using it and MaxPriorityFeePerGas
+// instead; otherwise it is a legacy transaction.
+func (c *Client) SignAndSend(signer Signer, opts *CallOpts) (Hash, error) {
+	from := signer.Address()
+	if opts.From != nil && *opts.From != from {
+		return Hash{}, fmt.Errorf("seth: signer address %x does not match CallOpts.From %x", from[:], opts.From[:])
+	}
+	opts.From = &from
+
+	if opts.Nonce == nil {
+		n, err := c.TransactionCount(&from, true)
+		if err != nil {
+			return Hash{}, err
+		}
+		opts.Nonce = &n
+	}
+	if opts.Gas == nil {
+		g, err := c.EstimateGas(opts)
+		if err != nil {
+			return Hash{}, err
+		}
+		opts.Gas = &g
+	}
+	chainID, err := c.ChainID()
+	if err != nil {
+		return Hash{}, err
+	}
+
+	var raw []byte
+	if opts.MaxFeePerGas != nil {
+		if opts.MaxPriorityFeePerGas == nil {
+			return Hash{}, errors.New("seth: MaxFeePerGas is set but MaxPriorityFeePerGas is not")
+		}
+		raw, err = signDynamicFeeTx(signer, opts, &chainID)
+	} else {
+		if opts.GasPrice == nil {
+			gp, gerr := c.GasPrice()
+			if gerr != nil {
+				return Hash{}, gerr
+			}
+			opts.GasPrice = &gp
+		}
+		raw, err = signLegacyTx(signer, opts, &chainID)
+	}
+	if err != nil {
+		return Hash{}, err
+	}
+
+	buf, _ := json.Marshal("0x" + hex.EncodeToString(raw))
+	var tx Hash
+	err = c.do("eth_sendRawTransaction", []json.RawMessage{buf}, &tx)
+	return tx, err
+}
+
+// signLegacyTx RLP-encodes and signs opts as a legacy transaction
+// with EIP-155 (https://eips.ethereum.org/EIPS/eip-155) replay
+// protection, returning the raw signed transaction bytes.
+func signLegacyTx(signer Signer, opts *CallOpts, chainID *Int) ([]byte, error) {
+	to := addressRLP(opts.To)
+	unsigned := rlpList(
+		rlpBig(intBig(opts.Nonce)),
+		rlpBig(intBig(opts.GasPrice)),
+		rlpBig(intBig(opts.Gas)),
+		to,
+		rlpBig(intBig(opts.Value)),
+		rlpBytes(opts.Data),
+		rlpBig(intBig(chainID)),
+		rlpBig(nil),
+		rlpBig(nil),
+	)
+	r, s, recID, err := sign(signer, unsigned)
+	if err != nil {
+		return nil, err
+	}
+	v := new(big.Int).Add(big.NewInt(int64(recID)+35), new(big.Int).Mul(intBig(chainID), big.NewInt(2)))
+
+	return rlpList(
+		rlpBig(intBig(opts.Nonce)),
+		rlpBig(intBig(opts.GasPrice)),
+		rlpBig(intBig(opts.Gas)),
+		to,
+		rlpBig(intBig(opts.Value)),
+		rlpBytes(opts.Data),
+		rlpBig(v),
+		rlpBig(r),
+		rlpBig(s),
+	), nil
+}
+
+// signDynamicFeeTx RLP-encodes and signs opts as an EIP-1559
+// (https://eips.ethereum.org/EIPS/eip-1559) type-2 transaction,
+// returning the raw signed transaction bytes (the 0x02 type prefix
+// followed by the signed payload's RLP encoding).
+func signDynamicFeeTx(signer Signer, opts *CallOpts, chainID *Int) ([]byte, error) {
+	to := addressRLP(opts.To)
+	accessList := rlpList()
+	payload := rlpList(
+		rlpBig(intBig(chainID)),
+		rlpBig(intBig(opts.Nonce)),
+		rlpBig(intBig(opts.MaxPriorityFeePerGas)),
+		rlpBig(intBig(opts.MaxFeePerGas)),
+		rlpBig(intBig(opts.Gas)),
+		to,
+		rlpBig(intBig(opts.Value)),
+		rlpBytes(opts.Data),
+		accessList,
+	)
+	r, s, recID, err := sign(signer, append([]byte{0x02}, payload...))
+	if err != nil {
+		return nil, err
+	}
+
+	signed := rlpList(
+		rlpBig(intBig(chainID)),
+		rlpBig(intBig(opts.Nonce)),
+		rlpBig(intBig(opts.MaxPriorityFeePerGas)),
+		rlpBig(intBig(opts.MaxFeePerGas)),
+		rlpBig(intBig(opts.Gas)),
+		to,
+		rlpBig(intBig(opts.Value)),
+		rlpBytes(opts.Data),
+		accessList,
+		rlpBig(big.NewInt(int64(recID))),
+		rlpBig(r),
+		rlpBig(s),
+	)
+	return append([]byte{0x02}, signed...), nil
+}
+
+// sign hashes msg with Keccak256 and asks signer to sign it,
+// splitting the result into its r, s and recovery-id components.
+func sign(signer Signer, msg []byte) (r, s *big.Int, recID byte, err error) {
+	sig, err := signer.SignHash(Keccak256(msg))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64]), sig[64], nil
+}
+
+// addressRLP RLP-encodes addr, or the empty string if addr is nil,
+// as used for CallOpts.To on a contract-creation transaction.
+func addressRLP(addr *Address) []byte {
+	if addr == nil {
+		return rlpBytes(nil)
+	}
+	return rlpBytes(addr[:])
+}
+
+// intBig returns i as a *big.Int, or nil if i is nil.
+func intBig(i *Int) *big.Int {
+	if i == nil {
+		return nil
+	}
+	return (*big.Int)(i)
+}
@@ -0,0 +1,102 @@
+package seth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrNoCodeAfterDeploy is returned by WaitDeployed when the mined
+// deployment transaction's receipt names a contract address that
+// holds no code, meaning the contract creation reverted, or names
+// no contract address at all.
+var ErrNoCodeAfterDeploy = errors.New("seth: no code at contract address after deploy")
+
+// Receipt is a mined transaction's receipt, as returned by
+// eth_getTransactionReceipt.
+type Receipt struct {
+	TransactionHash   Hash     `json:"transactionHash"`
+	TransactionIndex  Int      `json:"transactionIndex"`
+	BlockHash         Hash     `json:"blockHash"`
+	BlockNumber       Int      `json:"blockNumber"`
+	From              Address  `json:"from"`
+	To                *Address `json:"to"`
+	ContractAddress   *Address `json:"contractAddress"`
+	CumulativeGasUsed Int      `json:"cumulativeGasUsed"`
+	GasUsed           Int      `json:"gasUsed"`
+	Logs              []Log    `json:"logs"`
+	LogsBloom         Data     `json:"logsBloom"`
+	Status            Int      `json:"status"`
+}
+
+// TransactionReceipt fetches the receipt for tx, or returns a nil
+// Receipt and nil error if it hasn't been mined yet.
+func (c *Client) TransactionReceipt(tx Hash) (*Receipt, error) {
+	buf, _ := json.Marshal(tx)
+	var r *Receipt
+	err := c.do("eth_getTransactionReceipt", []json.RawMessage{buf}, &r)
+	return r, err
+}
+
+// WaitMined blocks, polling TransactionReceipt with a backoff, until
+// tx is mined into a block, and returns its receipt. It returns
+// ctx's error if ctx is done before that happens.
+func (c *Client) WaitMined(ctx context.Context, tx Hash) (*Receipt, error) {
+	const (
+		minPoll = 200 * time.Millisecond
+		maxPoll = 8 * time.Second
+	)
+	poll := minPoll
+	for {
+		r, err := c.TransactionReceipt(tx)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			return r, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(poll):
+		}
+		if poll *= 2; poll > maxPoll {
+			poll = maxPoll
+		}
+	}
+}
+
+// ContractAddress returns the address a contract-creation
+// transaction sent from sender with the given nonce will deploy to,
+// computed the same way as go-ethereum's crypto.CreateAddress:
+// the low 20 bytes of Keccak256(rlp([sender, nonce])).
+func ContractAddress(sender Address, nonce Int) Address {
+	enc := rlpList(rlpBytes(sender[:]), rlpBig(intBig(&nonce)))
+	hash := Keccak256(enc)
+	var addr Address
+	copy(addr[:], hash[12:])
+	return addr
+}
+
+// WaitDeployed blocks until the contract-creation transaction tx is
+// mined, then returns the address of the deployed contract. It
+// additionally confirms via eth_getCode that the address holds code,
+// returning ErrNoCodeAfterDeploy if the deployment reverted.
+func (c *Client) WaitDeployed(ctx context.Context, tx Hash) (Address, error) {
+	r, err := c.WaitMined(ctx, tx)
+	if err != nil {
+		return Address{}, err
+	}
+	if r.ContractAddress == nil {
+		return Address{}, ErrNoCodeAfterDeploy
+	}
+	code, err := c.GetCode(r.ContractAddress)
+	if err != nil {
+		return Address{}, err
+	}
+	if len(code) == 0 {
+		return Address{}, ErrNoCodeAfterDeploy
+	}
+	return *r.ContractAddress, nil
+}
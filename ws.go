@@ -0,0 +1,227 @@
+package seth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/net/websocket"
+)
+
+// WSTransport is a Transport that holds open a persistent WebSocket
+// connection to a node, the kind of connection eth_subscribe needs
+// in order to push notifications back to the client; HTTPTransport
+// can't carry those. Construct one with DialWS.
+type WSTransport struct {
+	conn *websocket.Conn
+
+	nextID uint64
+
+	mu       sync.Mutex
+	pending  map[uint64]chan rpcMessage
+	subs     map[string]*wsSub // subscription id -> notification sink
+	closed   chan struct{}
+	closeErr error
+}
+
+// wsSub guards delivery to, and closing of, one subscription's
+// notification channel with its own lock rather than WSTransport's
+// w.mu, so a consumer that isn't draining its channel never leaves
+// w.mu held: Do, subscribe and unsub can still take it. readLoop
+// itself does wait in send until the channel has room, and reads no
+// further messages from the connection meanwhile, so responses to
+// pending Do calls wait too.
+type wsSub struct {
+	mu     sync.Mutex
+	ch     chan json.RawMessage
+	closed bool
+}
+
+// send delivers v to the subscription, unless it has already been
+// closed. It may block if the channel's buffer is full.
+func (s *wsSub) send(v json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.ch <- v
+	}
+}
+
+// close closes the subscription's channel, if it hasn't been
+// already, unblocking anything ranging over it.
+func (s *wsSub) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		close(s.ch)
+		s.closed = true
+	}
+}
+
+// DialWS opens a WebSocket JSON-RPC connection to url (e.g.
+// "ws://localhost:8546").
+func DialWS(url string) (*WSTransport, error) {
+	conn, err := websocket.Dial(url, "", "http://localhost/")
+	if err != nil {
+		return nil, fmt.Errorf("seth: dialing %s: %w", url, err)
+	}
+	w := &WSTransport{
+		conn:    conn,
+		pending: make(map[uint64]chan rpcMessage),
+		subs:    make(map[string]*wsSub),
+		closed:  make(chan struct{}),
+	}
+	go w.readLoop()
+	return w, nil
+}
+
+// rpcRequest is an outgoing JSON-RPC 2.0 call.
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      uint64            `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+// rpcMessage is shaped to decode either a call response (Result/
+// Error, matched to its request by ID) or an eth_subscription
+// notification pushed by the node (Method == "eth_subscription",
+// Params holding {"subscription", "result"}).
+type rpcMessage struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Params json.RawMessage `json:"params"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return fmt.Sprintf("seth: rpc error %d: %s", e.Code, e.Message) }
+
+type subscriptionNotification struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// readLoop delivers each incoming message to the pending call or
+// subscription it belongs to. It runs until the connection closes,
+// at which point every still-open channel is closed so any caller
+// blocked in Do or reading a subscription channel unblocks.
+func (w *WSTransport) readLoop() {
+	for {
+		var msg rpcMessage
+		if err := websocket.JSON.Receive(w.conn, &msg); err != nil {
+			w.mu.Lock()
+			w.closeErr = err
+			for id, ch := range w.pending {
+				close(ch)
+				delete(w.pending, id)
+			}
+			for id, sub := range w.subs {
+				sub.close()
+				delete(w.subs, id)
+			}
+			w.mu.Unlock()
+			close(w.closed)
+			return
+		}
+		if msg.Method == "eth_subscription" {
+			var n subscriptionNotification
+			if json.Unmarshal(msg.Params, &n) != nil {
+				continue
+			}
+			// Only the map lookup happens under w.mu; the possibly-
+			// blocking send uses sub's own lock, so a slow consumer
+			// on this subscription never leaves w.mu held (readLoop
+			// itself still waits in send until there is room).
+			w.mu.Lock()
+			sub := w.subs[n.Subscription]
+			w.mu.Unlock()
+			if sub != nil {
+				sub.send(n.Result)
+			}
+			continue
+		}
+		w.mu.Lock()
+		ch := w.pending[msg.ID]
+		delete(w.pending, msg.ID)
+		w.mu.Unlock()
+		if ch != nil {
+			ch <- msg
+		}
+	}
+}
+
+// Do implements Transport.
+func (w *WSTransport) Do(method string, params []json.RawMessage) (json.RawMessage, error) {
+	if params == nil {
+		params = []json.RawMessage{}
+	}
+	id := atomic.AddUint64(&w.nextID, 1)
+	ch := make(chan rpcMessage, 1)
+	w.mu.Lock()
+	w.pending[id] = ch
+	w.mu.Unlock()
+
+	req := rpcRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}
+	if err := websocket.JSON.Send(w.conn, req); err != nil {
+		w.mu.Lock()
+		delete(w.pending, id)
+		w.mu.Unlock()
+		return nil, fmt.Errorf("seth: sending %s: %w", method, err)
+	}
+
+	msg, ok := <-ch
+	if !ok {
+		return nil, w.closeErr
+	}
+	if msg.Error != nil {
+		return nil, msg.Error
+	}
+	return msg.Result, nil
+}
+
+// subscribe sends eth_subscribe(kind, params...) and returns a
+// channel delivering each notification's "result" payload in turn,
+// along with a function that unsubscribes and stops delivery.
+func (w *WSTransport) subscribe(ctx context.Context, kind string, params []json.RawMessage) (<-chan json.RawMessage, func(), error) {
+	raw, err := w.Do("eth_subscribe", params)
+	if err != nil {
+		return nil, nil, err
+	}
+	var id string
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return nil, nil, fmt.Errorf("seth: decoding %s subscription id: %w", kind, err)
+	}
+
+	sub := &wsSub{ch: make(chan json.RawMessage, 16)}
+	w.mu.Lock()
+	w.subs[id] = sub
+	w.mu.Unlock()
+
+	var once sync.Once
+	unsub := func() {
+		once.Do(func() {
+			w.mu.Lock()
+			delete(w.subs, id)
+			w.mu.Unlock()
+			sub.close()
+			buf, _ := json.Marshal(id)
+			w.Do("eth_unsubscribe", []json.RawMessage{buf})
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			unsub()
+		case <-w.closed:
+		}
+	}()
+	return sub.ch, unsub, nil
+}